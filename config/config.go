@@ -0,0 +1,142 @@
+// Package config loads otel-sla's runtime configuration from a YAML file
+// (mirroring the shape of go.opentelemetry.io/contrib/config) with
+// environment variable overrides, so operators aren't limited to the
+// previously hard-coded service name, endpoint, and collection interval.
+package config
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// defaultConfigPath is where Load looks for a YAML config file unless
+// CONFIG_FILE overrides it; a missing file at this path is not an error.
+const defaultConfigPath = "otel-sla-config.yaml"
+
+// ViewConfig customizes how a single instrument is aggregated and exported,
+// surfaced as metricsdk.WithView options by the caller.
+type ViewConfig struct {
+	Instrument               string    `yaml:"instrument"`
+	Rename                   string    `yaml:"rename,omitempty"`
+	DropAttributes           []string  `yaml:"drop_attributes,omitempty"`
+	ExplicitBucketBoundaries []float64 `yaml:"explicit_bucket_boundaries,omitempty"`
+}
+
+// Config is otel-sla's runtime configuration. Zero-value fields are filled
+// in by Load's defaults.
+type Config struct {
+	ServiceName        string            `yaml:"service_name"`
+	Exporter           string            `yaml:"exporter"`
+	Protocol           string            `yaml:"protocol"`
+	CollectionInterval time.Duration     `yaml:"collection_interval"`
+	Endpoint           string            `yaml:"endpoint"`
+	Insecure           bool              `yaml:"insecure"`
+	PrometheusListen   string            `yaml:"prometheus_listen_addr"`
+	ResourceAttributes map[string]string `yaml:"resource_attributes,omitempty"`
+	Views              []ViewConfig      `yaml:"views,omitempty"`
+}
+
+// defaultGRPCEndpoint and defaultHTTPEndpoint are the per-protocol collector
+// addresses the OTLP/gRPC and OTLP/HTTP drivers dial by default; Endpoint is
+// left unset by defaults() so Load can pick the right one for cfg.Protocol.
+const (
+	defaultGRPCEndpoint = "localhost:4317"
+	defaultHTTPEndpoint = "localhost:4318"
+)
+
+// defaults returns the configuration that reproduces otel-sla's original
+// hard-coded behavior: OTLP/gRPC to localhost:4317 every 10 seconds.
+func defaults() Config {
+	return Config{
+		ServiceName:        "otel-sla",
+		Exporter:           "otlpgrpc",
+		Protocol:           "grpc",
+		CollectionInterval: 10 * time.Second,
+		Insecure:           true,
+		PrometheusListen:   ":9464",
+	}
+}
+
+// Load builds a Config by starting from defaults, overlaying a YAML file
+// (CONFIG_FILE, or defaultConfigPath if present), and finally applying
+// environment variable overrides so OTEL_* and otel-sla's own env vars keep
+// working with no config file at all.
+func Load() (*Config, error) {
+	cfg := defaults()
+
+	if err := loadFile(&cfg); err != nil {
+		return nil, err
+	}
+	applyEnvOverrides(&cfg)
+
+	if cfg.Endpoint == "" {
+		cfg.Endpoint = defaultEndpoint(cfg.Protocol)
+	}
+
+	return &cfg, nil
+}
+
+// defaultEndpoint picks the gRPC or HTTP driver's own default port, so
+// switching protocol without also setting an endpoint doesn't silently dial
+// the wrong port.
+func defaultEndpoint(protocol string) string {
+	if protocol == "http" {
+		return defaultHTTPEndpoint
+	}
+	return defaultGRPCEndpoint
+}
+
+// loadFile overlays a YAML config file onto cfg, if one is present.
+func loadFile(cfg *Config) error {
+	path := os.Getenv("CONFIG_FILE")
+	if path == "" {
+		path = defaultConfigPath
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return fmt.Errorf("could not read config file %q: %w", path, err)
+	}
+
+	if err := yaml.Unmarshal(data, cfg); err != nil {
+		return fmt.Errorf("could not parse config file %q: %w", path, err)
+	}
+	return nil
+}
+
+// applyEnvOverrides layers environment variables on top of cfg, preserving
+// the env var names otel-sla already documented for each setting.
+func applyEnvOverrides(cfg *Config) {
+	if v := os.Getenv("EXPORTER"); v != "" {
+		cfg.Exporter = v
+	}
+	if v := os.Getenv("PROTOCOL"); v != "" {
+		cfg.Protocol = v
+	}
+	if v := os.Getenv("OTEL_EXPORTER_OTLP_METRICS_ENDPOINT"); v != "" {
+		cfg.Endpoint = v
+	} else if v := os.Getenv("OTEL_EXPORTER_OTLP_ENDPOINT"); v != "" {
+		cfg.Endpoint = v
+	}
+	if v := os.Getenv("OTEL_EXPORTER_OTLP_INSECURE"); v != "" {
+		cfg.Insecure = v == "true"
+	}
+	if v := os.Getenv("PROMETHEUS_LISTEN_ADDR"); v != "" {
+		cfg.PrometheusListen = v
+	}
+	if v := os.Getenv("OTEL_SERVICE_NAME"); v != "" {
+		cfg.ServiceName = v
+	}
+	if v := os.Getenv("COLLECTION_INTERVAL"); v != "" {
+		if seconds, err := strconv.Atoi(v); err == nil {
+			cfg.CollectionInterval = time.Duration(seconds) * time.Second
+		}
+	}
+}
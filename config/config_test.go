@@ -0,0 +1,107 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestLoadDefaults(t *testing.T) {
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+
+	if cfg.ServiceName != "otel-sla" {
+		t.Errorf("ServiceName = %q, want %q", cfg.ServiceName, "otel-sla")
+	}
+	if cfg.Protocol != "grpc" {
+		t.Errorf("Protocol = %q, want %q", cfg.Protocol, "grpc")
+	}
+	if cfg.Endpoint != defaultGRPCEndpoint {
+		t.Errorf("Endpoint = %q, want %q", cfg.Endpoint, defaultGRPCEndpoint)
+	}
+	if cfg.CollectionInterval != 10*time.Second {
+		t.Errorf("CollectionInterval = %v, want 10s", cfg.CollectionInterval)
+	}
+}
+
+func TestLoadEndpointDefaultsFollowProtocol(t *testing.T) {
+	t.Setenv("PROTOCOL", "http")
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+
+	if cfg.Endpoint != defaultHTTPEndpoint {
+		t.Errorf("Endpoint = %q, want %q (the HTTP default, not the gRPC one)", cfg.Endpoint, defaultHTTPEndpoint)
+	}
+}
+
+func TestLoadFileOverridesDefaults(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "otel-sla-config.yaml")
+	writeFile(t, path, "service_name: from-file\nprotocol: http\n")
+	t.Setenv("CONFIG_FILE", path)
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+
+	if cfg.ServiceName != "from-file" {
+		t.Errorf("ServiceName = %q, want %q", cfg.ServiceName, "from-file")
+	}
+	if cfg.Protocol != "http" {
+		t.Errorf("Protocol = %q, want %q", cfg.Protocol, "http")
+	}
+	if cfg.Endpoint != defaultHTTPEndpoint {
+		t.Errorf("Endpoint = %q, want the http-protocol default %q", cfg.Endpoint, defaultHTTPEndpoint)
+	}
+}
+
+func TestLoadEnvOverridesFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "otel-sla-config.yaml")
+	writeFile(t, path, "service_name: from-file\n")
+	t.Setenv("CONFIG_FILE", path)
+	t.Setenv("OTEL_SERVICE_NAME", "from-env")
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+
+	if cfg.ServiceName != "from-env" {
+		t.Errorf("ServiceName = %q, want env override %q", cfg.ServiceName, "from-env")
+	}
+}
+
+func TestLoadMetricsEndpointTakesPrecedenceOverGenericEndpoint(t *testing.T) {
+	t.Setenv("OTEL_EXPORTER_OTLP_ENDPOINT", "generic:4317")
+	t.Setenv("OTEL_EXPORTER_OTLP_METRICS_ENDPOINT", "metrics-specific:4317")
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+
+	if cfg.Endpoint != "metrics-specific:4317" {
+		t.Errorf("Endpoint = %q, want the metrics-specific override to win", cfg.Endpoint)
+	}
+}
+
+func TestLoadMissingConfigFileIsNotAnError(t *testing.T) {
+	t.Setenv("CONFIG_FILE", filepath.Join(t.TempDir(), "does-not-exist.yaml"))
+
+	if _, err := Load(); err != nil {
+		t.Errorf("Load() error = %v, want nil for a missing config file", err)
+	}
+}
+
+func writeFile(t *testing.T, path, contents string) {
+	t.Helper()
+	if err := os.WriteFile(path, []byte(contents), 0644); err != nil {
+		t.Fatalf("could not write test config file: %v", err)
+	}
+}
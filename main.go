@@ -2,209 +2,382 @@ package main
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
 	"log"
+	"log/slog"
+	"net/http"
 	"os"
-	"runtime"
+	"os/signal"
+	"strings"
+	"syscall"
 	"time"
 
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"github.com/srimandarbha/metrics_sla/config"
+	"github.com/srimandarbha/metrics_sla/otelslog"
+	"github.com/srimandarbha/metrics_sla/runtimemetrics"
 	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/exporters/otlp/otlpmetric/otlpmetricgrpc"
+	"go.opentelemetry.io/otel/exporters/otlp/otlpmetric/otlpmetrichttp"
+	otelprometheus "go.opentelemetry.io/otel/exporters/prometheus"
 	"go.opentelemetry.io/otel/metric"
 	metricsdk "go.opentelemetry.io/otel/sdk/metric"
 	"go.opentelemetry.io/otel/sdk/resource"
 	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
 )
 
-// Log file path
-const logFilePath = "otel_sla_logs.json"
-
-// Struct for JSON log entry
-type LogEntry struct {
-	Message            string `json:"message"`
-	CollectorTimestamp string `json:"collector_timestamp"`
-}
+// Exporter kinds selectable via cfg.Exporter.
+const (
+	exporterOTLPGRPC   = "otlpgrpc"
+	exporterPrometheus = "prometheus"
+)
 
-// Function to write JSON log to a file
-func logToFile(entry LogEntry) {
-	file, err := os.OpenFile(logFilePath, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+// Creates and configures a new Meter Provider, along with the *resource.Resource
+// it was built against so callers needing the same resource (e.g. the logger
+// provider) don't have to re-run the detectors. The returned *http.Server is
+// non-nil only in Prometheus mode, where it's the /metrics listener the
+// caller must Shutdown alongside the provider.
+func newMeterProvider(ctx context.Context, cfg *config.Config) (*metricsdk.MeterProvider, *resource.Resource, *http.Server, error) {
+	res, err := getResource(ctx, cfg)
 	if err != nil {
-		log.Printf("Failed to open log file: %v", err)
-		return
+		return nil, nil, nil, fmt.Errorf("could not get resource: %w", err)
 	}
-	defer file.Close()
 
-	jsonData, err := json.Marshal(entry)
-	if err != nil {
-		log.Printf("Failed to marshal JSON: %v", err)
-		return
+	var reader metricsdk.Reader
+	var promServer *http.Server
+	if cfg.Exporter == exporterPrometheus {
+		reader, promServer, err = newPrometheusReader(cfg)
+		if err != nil {
+			return nil, nil, nil, fmt.Errorf("could not create prometheus reader: %w", err)
+		}
+	} else {
+		collectorExporter, err := getOtelMetricsCollectorExporter(ctx, cfg)
+		if err != nil {
+			return nil, nil, nil, fmt.Errorf("could not get collector exporter: %w", err)
+		}
+		reader = metricsdk.NewPeriodicReader(collectorExporter, metricsdk.WithInterval(cfg.CollectionInterval))
 	}
 
-	// Append newline for each log entry
-	_, err = file.WriteString(string(jsonData) + "\n")
-	if err != nil {
-		log.Printf("Failed to write log entry: %v", err)
+	opts := []metricsdk.Option{
+		metricsdk.WithResource(res),
+		metricsdk.WithReader(reader),
 	}
+	opts = append(opts, buildViews(cfg)...)
+
+	return metricsdk.NewMeterProvider(opts...), res, promServer, nil
 }
 
-// Creates and configures a new Meter Provider
-func newMeterProvider(ctx context.Context) (metric.MeterProvider, error) {
-	interval := 10 * time.Second
+// buildViews turns cfg.Views into metricsdk.Option values so users can
+// rename instruments, drop high-cardinality attributes, or change
+// aggregation (e.g. explicit-bucket histograms) without code changes.
+func buildViews(cfg *config.Config) []metricsdk.Option {
+	views := make([]metricsdk.Option, 0, len(cfg.Views))
+	for _, v := range cfg.Views {
+		v := v
+		stream := metricsdk.Stream{Name: v.Rename}
+
+		if len(v.DropAttributes) > 0 {
+			drop := make(map[string]struct{}, len(v.DropAttributes))
+			for _, name := range v.DropAttributes {
+				drop[name] = struct{}{}
+			}
+			stream.AttributeFilter = func(kv attribute.KeyValue) bool {
+				_, dropped := drop[string(kv.Key)]
+				return !dropped
+			}
+		}
 
-	res, err := getResource()
-	if err != nil {
-		return nil, fmt.Errorf("could not get resource: %w", err)
+		if len(v.ExplicitBucketBoundaries) > 0 {
+			stream.Aggregation = metricsdk.AggregationExplicitBucketHistogram{
+				Boundaries: v.ExplicitBucketBoundaries,
+			}
+		}
+
+		views = append(views, metricsdk.WithView(metricsdk.NewView(
+			metricsdk.Instrument{Name: v.Instrument},
+			stream,
+		)))
 	}
+	return views
+}
+
+// newPrometheusReader builds a metricsdk.Reader backed by a dedicated
+// Prometheus registry and starts an HTTP server exposing it on /metrics, so
+// the sidecar can be scraped directly without a collector in front of it.
+// The returned *http.Server is the caller's responsibility to Shutdown.
+func newPrometheusReader(cfg *config.Config) (metricsdk.Reader, *http.Server, error) {
+	registry := prometheus.NewRegistry()
 
-	collectorExporter, err := getOtelMetricsCollectorExporter(ctx)
+	reader, err := otelprometheus.New(otelprometheus.WithRegisterer(registry))
 	if err != nil {
-		return nil, fmt.Errorf("could not get collector exporter: %w", err)
+		return nil, nil, fmt.Errorf("could not create prometheus exporter: %w", err)
 	}
 
-	periodicReader := metricsdk.NewPeriodicReader(collectorExporter, metricsdk.WithInterval(interval))
+	server := startPrometheusServer(registry, cfg.PrometheusListen)
 
-	provider := metricsdk.NewMeterProvider(
-		metricsdk.WithResource(res),
-		metricsdk.WithReader(periodicReader),
-	)
+	return reader, server, nil
+}
 
-	return provider, nil
+// startPrometheusServer serves promhttp.Handler() for the given registry on
+// addr and returns the server so the caller can shut it down on exit.
+func startPrometheusServer(registry *prometheus.Registry, addr string) *http.Server {
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", promhttp.HandlerFor(registry, promhttp.HandlerOpts{}))
+
+	server := &http.Server{Addr: addr, Handler: mux}
+
+	go func() {
+		if err := server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			log.Printf("Prometheus metrics server stopped: %v", err)
+		}
+	}()
+
+	return server
 }
 
-// Creates a new Meter
-func NewMeter(ctx context.Context) (metric.Meter, error) {
-	provider, err := newMeterProvider(ctx)
+// Creates a new Meter, along with the MeterProvider it came from (so the
+// caller can flush and shut it down), the *resource.Resource it was built
+// against, and the Prometheus /metrics server (non-nil only in Prometheus
+// mode).
+func NewMeter(ctx context.Context, cfg *config.Config) (metric.Meter, *metricsdk.MeterProvider, *resource.Resource, *http.Server, error) {
+	provider, res, promServer, err := newMeterProvider(ctx, cfg)
 	if err != nil {
-		return nil, fmt.Errorf("could not create meter provider: %w", err)
+		return nil, nil, nil, nil, fmt.Errorf("could not create meter provider: %w", err)
 	}
 
 	otel.SetMeterProvider(provider)
 
-	return provider.Meter("otel_sla"), nil
+	return provider.Meter("otel_sla"), provider, res, promServer, nil
 }
 
-// Returns a resource with additional attributes
-func getResource() (*resource.Resource, error) {
-	hostname, _ := os.Hostname()
-	res, err := resource.Merge(
-		resource.Default(),
-		resource.NewWithAttributes(
-			semconv.SchemaURL,
-			semconv.ServiceNameKey.String("otel-sla"),
-			attribute.String("host.name", hostname),
-			attribute.String("os.type", runtime.GOOS),
-		),
+// getResource builds the process resource using the standard OTel detectors
+// (host, process, container, OTEL_RESOURCE_ATTRIBUTES via WithFromEnv)
+// instead of a manual os.Hostname/runtime.GOOS merge, plus cfg's service
+// name and any extra resource attributes from the config file.
+func getResource(ctx context.Context, cfg *config.Config) (*resource.Resource, error) {
+	extra := make([]attribute.KeyValue, 0, len(cfg.ResourceAttributes)+1)
+	extra = append(extra, semconv.ServiceNameKey.String(cfg.ServiceName))
+	for k, v := range cfg.ResourceAttributes {
+		extra = append(extra, attribute.String(k, v))
+	}
+
+	res, err := resource.New(ctx,
+		resource.WithFromEnv(),
+		resource.WithTelemetrySDK(),
+		resource.WithHost(),
+		resource.WithProcess(),
+		resource.WithContainer(),
+		resource.WithAttributes(extra...),
 	)
 	if err != nil {
-		return nil, fmt.Errorf("could not merge resources: %w", err)
+		return nil, fmt.Errorf("could not build resource: %w", err)
 	}
 	return res, nil
 }
 
-// Creates an OTLP metrics exporter
-func getOtelMetricsCollectorExporter(ctx context.Context) (metricsdk.Exporter, error) {
+// getOtelMetricsCollectorExporter is the factory for the OTLP metric
+// exporter: it picks the gRPC or HTTP driver based on cfg.Protocol and hands
+// back a metricsdk.Exporter that newMeterProvider wraps in a single shared
+// NewPeriodicReader.
+func getOtelMetricsCollectorExporter(ctx context.Context, cfg *config.Config) (metricsdk.Exporter, error) {
 	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
 	defer cancel()
 
-	exporter, err := otlpmetricgrpc.New(ctx,
-		otlpmetricgrpc.WithEndpoint("localhost:4317"),
+	if cfg.Protocol == protocolHTTP {
+		return newOTLPHTTPExporter(ctx, cfg)
+	}
+	return newOTLPGRPCExporter(ctx, cfg)
+}
+
+// Protocols accepted by cfg.Protocol for the OTLP driver.
+const (
+	protocolGRPC = "grpc"
+	protocolHTTP = "http"
+)
+
+// newOTLPGRPCExporter builds the gRPC driver, defaulting to the original
+// insecure localhost:4317 collector but honoring cfg.Endpoint.
+func newOTLPGRPCExporter(ctx context.Context, cfg *config.Config) (metricsdk.Exporter, error) {
+	opts := []otlpmetricgrpc.Option{
+		otlpmetricgrpc.WithEndpoint(cfg.Endpoint),
 		otlpmetricgrpc.WithCompressor("gzip"),
-		otlpmetricgrpc.WithInsecure(),
-	)
+	}
 
+	if cfg.Insecure {
+		opts = append(opts, otlpmetricgrpc.WithInsecure())
+	}
+	if headers := otlpHeaders(); len(headers) > 0 {
+		opts = append(opts, otlpmetricgrpc.WithHeaders(headers))
+	}
+	opts = append(opts, otlpmetricgrpc.WithRetry(otlpmetricgrpc.RetryConfig{
+		Enabled:         true,
+		InitialInterval: time.Second,
+		MaxInterval:     5 * time.Second,
+		MaxElapsedTime:  time.Minute,
+	}))
+
+	exporter, err := otlpmetricgrpc.New(ctx, opts...)
 	if err != nil {
-		return nil, fmt.Errorf("could not create metric exporter: %w", err)
+		return nil, fmt.Errorf("could not create grpc metric exporter: %w", err)
 	}
 	return exporter, nil
 }
 
-// Collects and reports memory usage periodically
-func collectMachineResourceMetrics(ctx context.Context, meter metric.Meter) {
-	var Mb uint64 = 1_048_576 // Convert bytes to MB
-
-	// Create observable gauge for memory usage
-	memGauge, err := meter.Float64ObservableGauge(
-		"otel.sla.metric",
-		metric.WithDescription("Allocated memory in MB"),
-		metric.WithUnit("MB"),
-	)
-	if err != nil {
-		log.Printf("Failed to create memory gauge: %v", err)
-		return
-	}
-
-	// Register callback to observe memory stats
-	_, err = meter.RegisterCallback(func(ctx context.Context, o metric.Observer) error {
-		var memStats runtime.MemStats
-		runtime.ReadMemStats(&memStats)
-		allocatedMemoryInMB := float64(memStats.Alloc) / float64(Mb)
-		o.ObserveFloat64(memGauge, allocatedMemoryInMB, metric.WithAttributes(
-			attribute.String("metric_generation_time", time.Now().Format(time.RFC3339)),
-		))
-		return nil
-	}, memGauge)
+// newOTLPHTTPExporter builds the HTTP driver for backends (e.g. SaaS
+// collectors) that prefer OTLP/HTTP, including TLS, auth headers, and retry.
+func newOTLPHTTPExporter(ctx context.Context, cfg *config.Config) (metricsdk.Exporter, error) {
+	opts := []otlpmetrichttp.Option{
+		otlpmetrichttp.WithEndpoint(cfg.Endpoint),
+		otlpmetrichttp.WithCompression(otlpmetrichttp.GzipCompression),
+	}
 
+	if cfg.Insecure {
+		opts = append(opts, otlpmetrichttp.WithInsecure())
+	}
+	if headers := otlpHeaders(); len(headers) > 0 {
+		opts = append(opts, otlpmetrichttp.WithHeaders(headers))
+	}
+	opts = append(opts, otlpmetrichttp.WithRetry(otlpmetrichttp.RetryConfig{
+		Enabled:         true,
+		InitialInterval: time.Second,
+		MaxInterval:     5 * time.Second,
+		MaxElapsedTime:  time.Minute,
+	}))
+
+	exporter, err := otlpmetrichttp.New(ctx, opts...)
 	if err != nil {
-		log.Printf("Failed to register memory callback: %v", err)
+		return nil, fmt.Errorf("could not create http metric exporter: %w", err)
 	}
+	return exporter, nil
 }
 
-// Increments the memory counter periodically and logs JSON to a file
-func startMemoryCounter(ctx context.Context, meter metric.Meter) {
-	counter, err := meter.Int64Counter(
-		"allocated_memory_in_mb",
-		metric.WithDescription("Total allocated memory in MB"),
-		metric.WithUnit("MB"),
-	)
-	if err != nil {
-		log.Printf("Failed to create memory counter: %v", err)
-		return
+// otlpHeaders parses OTEL_EXPORTER_OTLP_HEADERS (a comma-separated list of
+// key=value pairs) for authenticating to SaaS backends.
+func otlpHeaders() map[string]string {
+	raw := os.Getenv("OTEL_EXPORTER_OTLP_HEADERS")
+	if raw == "" {
+		return nil
 	}
 
+	headers := make(map[string]string)
+	for _, pair := range strings.Split(raw, ",") {
+		key, value, ok := strings.Cut(pair, "=")
+		if !ok {
+			continue
+		}
+		headers[strings.TrimSpace(key)] = strings.TrimSpace(value)
+	}
+	return headers
+}
+
+// startHeartbeat periodically emits a log record through logger until ctx is
+// cancelled, replacing the old hand-rolled JSON-file heartbeat.
+func startHeartbeat(ctx context.Context, logger *slog.Logger) {
 	go func() {
+		ticker := time.NewTicker(5 * time.Second)
+		defer ticker.Stop()
+
 		for {
 			select {
 			case <-ctx.Done():
 				return
-			default:
-				var memStats runtime.MemStats
-				runtime.ReadMemStats(&memStats)
-				allocatedMemoryInMB := int64(memStats.Alloc / 1_048_576)
-
-				// Increment the counter
-				counter.Add(ctx, allocatedMemoryInMB, metric.WithAttributes(
-					attribute.String("metric_collection_time", time.Now().Format(time.RFC3339)),
-				))
-
-				// Log JSON entry to file
-				logToFile(LogEntry{
-					Message:            "otel-sla-logs",
-					CollectorTimestamp: time.Now().Format(time.RFC3339),
-				})
-
-				time.Sleep(5 * time.Second)
+			case <-ticker.C:
+				logger.InfoContext(ctx, "otel-sla-logs")
 			}
 		}
 	}()
 }
 
-func main() {
-	ctx, cancel := context.WithCancel(context.Background())
+// forceFlushTimeout bounds how long Shutdown waits for in-flight batches to
+// reach the collector before tearing the providers down.
+const forceFlushTimeout = 5 * time.Second
+
+// SLA owns the process-wide telemetry providers and background goroutines,
+// so they can be flushed and torn down together on shutdown.
+type SLA struct {
+	meterProvider  *metricsdk.MeterProvider
+	shutdownLogger func(context.Context) error
+	promServer     *http.Server
+
+	Meter  metric.Meter
+	Logger *slog.Logger
+}
+
+// NewSLA wires up the meter and logger providers and starts the background
+// telemetry goroutines, all tied to ctx so they stop when it's cancelled.
+func NewSLA(ctx context.Context, cfg *config.Config) (*SLA, error) {
+	meter, meterProvider, res, promServer, err := NewMeter(ctx, cfg)
+	if err != nil {
+		return nil, fmt.Errorf("could not create meter: %w", err)
+	}
+
+	logger, shutdownLogger, err := otelslog.NewLogger(ctx, cfg, res)
+	if err != nil {
+		return nil, fmt.Errorf("could not create logger: %w", err)
+	}
+
+	if err := runtimemetrics.NewTelemetryBuilder(meter).Register(); err != nil {
+		return nil, fmt.Errorf("could not register runtime telemetry: %w", err)
+	}
+
+	startHeartbeat(ctx, logger)
+
+	return &SLA{
+		meterProvider:  meterProvider,
+		shutdownLogger: shutdownLogger,
+		promServer:     promServer,
+		Meter:          meter,
+		Logger:         logger,
+	}, nil
+}
+
+// Shutdown force-flushes any pending metrics and logs, bounded by
+// forceFlushTimeout, then shuts down both providers and the Prometheus
+// /metrics server, if one was started.
+func (s *SLA) Shutdown(ctx context.Context) error {
+	flushCtx, cancel := context.WithTimeout(ctx, forceFlushTimeout)
 	defer cancel()
 
-	meter, err := NewMeter(ctx)
+	if err := s.meterProvider.ForceFlush(flushCtx); err != nil {
+		return fmt.Errorf("could not flush meter provider: %w", err)
+	}
+	if err := s.meterProvider.Shutdown(flushCtx); err != nil {
+		return fmt.Errorf("could not shut down meter provider: %w", err)
+	}
+	if s.promServer != nil {
+		if err := s.promServer.Shutdown(flushCtx); err != nil {
+			return fmt.Errorf("could not shut down prometheus server: %w", err)
+		}
+	}
+	if err := s.shutdownLogger(flushCtx); err != nil {
+		return fmt.Errorf("could not shut down logger provider: %w", err)
+	}
+	return nil
+}
+
+func main() {
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	cfg, err := config.Load()
 	if err != nil {
-		log.Fatalf("Could not create meter: %v", err)
+		log.Fatalf("Could not load config: %v", err)
 	}
 
-	// Start collecting memory metrics
-	collectMachineResourceMetrics(ctx, meter)
+	sla, err := NewSLA(ctx, cfg)
+	if err != nil {
+		log.Fatalf("Could not start SLA telemetry: %v", err)
+	}
 
-	// Start counter updates and JSON logging
-	startMemoryCounter(ctx, meter)
+	// Block until SIGINT/SIGTERM, then flush and shut everything down.
+	<-ctx.Done()
 
-	// Keep the application running
-	select {}
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), forceFlushTimeout+5*time.Second)
+	defer cancel()
+
+	if err := sla.Shutdown(shutdownCtx); err != nil {
+		log.Printf("Error during shutdown: %v", err)
+	}
 }
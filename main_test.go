@@ -0,0 +1,143 @@
+package main
+
+import (
+	"context"
+	"testing"
+
+	"github.com/srimandarbha/metrics_sla/config"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	metricsdk "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+)
+
+func TestBuildViews(t *testing.T) {
+	cfg := &config.Config{
+		Views: []config.ViewConfig{
+			{
+				Instrument:     "test.counter",
+				Rename:         "test.counter.renamed",
+				DropAttributes: []string{"drop.me"},
+			},
+			{
+				Instrument:               "test.histogram",
+				ExplicitBucketBoundaries: []float64{1, 5, 10},
+			},
+		},
+	}
+
+	reader := metricsdk.NewManualReader()
+	provider := metricsdk.NewMeterProvider(append(
+		[]metricsdk.Option{metricsdk.WithReader(reader)},
+		buildViews(cfg)...,
+	)...)
+	t.Cleanup(func() { _ = provider.Shutdown(context.Background()) })
+
+	meter := provider.Meter("main-test")
+
+	counter, err := meter.Float64Counter("test.counter")
+	if err != nil {
+		t.Fatalf("Float64Counter() error = %v", err)
+	}
+	counter.Add(context.Background(), 1, metric.WithAttributes(
+		attribute.String("drop.me", "should be filtered out"),
+		attribute.String("keep.me", "kept"),
+	))
+
+	histogram, err := meter.Float64Histogram("test.histogram")
+	if err != nil {
+		t.Fatalf("Float64Histogram() error = %v", err)
+	}
+	histogram.Record(context.Background(), 3)
+
+	var rm metricdata.ResourceMetrics
+	if err := reader.Collect(context.Background(), &rm); err != nil {
+		t.Fatalf("Collect() error = %v", err)
+	}
+
+	sum := findSum(t, rm, "test.counter.renamed")
+	if len(sum.DataPoints) != 1 {
+		t.Fatalf("test.counter.renamed has %d data points, want 1", len(sum.DataPoints))
+	}
+	dp := sum.DataPoints[0]
+	if _, ok := dp.Attributes.Value(attribute.Key("drop.me")); ok {
+		t.Errorf("test.counter.renamed data point still has drop.me attribute: %v", dp.Attributes)
+	}
+	if v, ok := dp.Attributes.Value(attribute.Key("keep.me")); !ok || v.AsString() != "kept" {
+		t.Errorf("test.counter.renamed data point missing keep.me attribute: %v", dp.Attributes)
+	}
+
+	hist := findHistogram(t, rm, "test.histogram")
+	if len(hist.DataPoints) != 1 {
+		t.Fatalf("test.histogram has %d data points, want 1", len(hist.DataPoints))
+	}
+	bounds := hist.DataPoints[0].Bounds
+	want := []float64{1, 5, 10}
+	if len(bounds) != len(want) {
+		t.Fatalf("test.histogram bounds = %v, want %v", bounds, want)
+	}
+	for i, b := range want {
+		if bounds[i] != b {
+			t.Errorf("test.histogram bounds[%d] = %v, want %v", i, bounds[i], b)
+		}
+	}
+}
+
+func findSum(t *testing.T, rm metricdata.ResourceMetrics, name string) metricdata.Sum[float64] {
+	t.Helper()
+	for _, sm := range rm.ScopeMetrics {
+		for _, m := range sm.Metrics {
+			if m.Name != name {
+				continue
+			}
+			if sum, ok := m.Data.(metricdata.Sum[float64]); ok {
+				return sum
+			}
+		}
+	}
+	t.Fatalf("sum metric %q not found", name)
+	return metricdata.Sum[float64]{}
+}
+
+// TestNewMeterProviderPrometheusStartsAndStopsServer exercises the
+// Prometheus exporter path, which needs no live collector: it's the
+// regression 4b79984 had to fix after the first pass forgot to track the
+// /metrics server, so the server must come back non-nil and shut down
+// cleanly.
+func TestNewMeterProviderPrometheusStartsAndStopsServer(t *testing.T) {
+	cfg := &config.Config{
+		Exporter:         exporterPrometheus,
+		PrometheusListen: "127.0.0.1:0",
+	}
+
+	provider, _, promServer, err := newMeterProvider(context.Background(), cfg)
+	if err != nil {
+		t.Fatalf("newMeterProvider() error = %v", err)
+	}
+	if promServer == nil {
+		t.Fatal("newMeterProvider() returned a nil *http.Server for prometheus mode")
+	}
+
+	if err := promServer.Shutdown(context.Background()); err != nil {
+		t.Errorf("promServer.Shutdown() error = %v", err)
+	}
+	if err := provider.Shutdown(context.Background()); err != nil {
+		t.Errorf("provider.Shutdown() error = %v", err)
+	}
+}
+
+func findHistogram(t *testing.T, rm metricdata.ResourceMetrics, name string) metricdata.Histogram[float64] {
+	t.Helper()
+	for _, sm := range rm.ScopeMetrics {
+		for _, m := range sm.Metrics {
+			if m.Name != name {
+				continue
+			}
+			if hist, ok := m.Data.(metricdata.Histogram[float64]); ok {
+				return hist
+			}
+		}
+	}
+	t.Fatalf("histogram metric %q not found", name)
+	return metricdata.Histogram[float64]{}
+}
@@ -0,0 +1,108 @@
+// Package otelslog wires an OTel-native log/slog handler in place of the
+// hand-rolled JSON file logger: records flow through an sdklog.LoggerProvider
+// and an OTLP log exporter, with an optional JSONL file sink for local dev.
+package otelslog
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"time"
+
+	"github.com/srimandarbha/metrics_sla/config"
+	otelslogbridge "go.opentelemetry.io/contrib/bridges/otelslog"
+	"go.opentelemetry.io/otel/exporters/otlp/otlplog/otlploggrpc"
+	"go.opentelemetry.io/otel/exporters/otlp/otlplog/otlploghttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdoutlog"
+	sdklog "go.opentelemetry.io/otel/sdk/log"
+	"go.opentelemetry.io/otel/sdk/resource"
+)
+
+// loggerName is the instrumentation scope name reported on every record.
+const loggerName = "otel_sla"
+
+// fileSinkPath is where the optional JSONL dev sink writes, matching the
+// file the previous hand-rolled logger used.
+const fileSinkPath = "otel_sla_logs.json"
+
+// NewLogger builds an OTLP-backed LoggerProvider (gRPC or HTTP, selected via
+// cfg the same way as the metric exporter) with an slog.Logger on top, plus
+// an optional JSONL file sink for local dev. The returned shutdown func
+// flushes and closes the provider's batch processors and must be called on
+// exit.
+func NewLogger(ctx context.Context, cfg *config.Config, res *resource.Resource) (*slog.Logger, func(context.Context) error, error) {
+	exporter, err := newOTLPLogExporter(ctx, cfg)
+	if err != nil {
+		return nil, nil, fmt.Errorf("could not create log exporter: %w", err)
+	}
+
+	opts := []sdklog.LoggerProviderOption{
+		sdklog.WithResource(res),
+		sdklog.WithProcessor(sdklog.NewBatchProcessor(exporter)),
+	}
+
+	if fileSinkEnabled() {
+		fileExporter, err := newFileSinkExporter()
+		if err != nil {
+			return nil, nil, fmt.Errorf("could not create file sink exporter: %w", err)
+		}
+		opts = append(opts, sdklog.WithProcessor(sdklog.NewBatchProcessor(fileExporter)))
+	}
+
+	provider := sdklog.NewLoggerProvider(opts...)
+
+	logger := otelslogbridge.NewLogger(loggerName, otelslogbridge.WithLoggerProvider(provider))
+
+	return logger, provider.Shutdown, nil
+}
+
+// fileSinkEnabled reports whether OTEL_SLA_LOG_FILE_SINK requests the
+// optional local JSONL sink, off by default.
+func fileSinkEnabled() bool {
+	return os.Getenv("OTEL_SLA_LOG_FILE_SINK") == "true"
+}
+
+// newFileSinkExporter opens fileSinkPath for append and wraps it in a
+// stdoutlog-style JSONL exporter, which NewLogger drives through its own
+// batch processor just like the OTLP exporter, so shutdown flushes both.
+func newFileSinkExporter() (sdklog.Exporter, error) {
+	file, err := os.OpenFile(fileSinkPath, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return nil, fmt.Errorf("could not open log sink file: %w", err)
+	}
+	return stdoutlog.New(stdoutlog.WithWriter(file))
+}
+
+// newOTLPLogExporter picks the gRPC or HTTP log exporter based on
+// cfg.Protocol, mirroring the metric exporter's protocol selection.
+func newOTLPLogExporter(ctx context.Context, cfg *config.Config) (sdklog.Exporter, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	endpoint := logsEndpoint(cfg)
+
+	if cfg.Protocol == "http" {
+		opts := []otlploghttp.Option{otlploghttp.WithEndpoint(endpoint)}
+		if cfg.Insecure {
+			opts = append(opts, otlploghttp.WithInsecure())
+		}
+		return otlploghttp.New(ctx, opts...)
+	}
+
+	opts := []otlploggrpc.Option{otlploggrpc.WithEndpoint(endpoint)}
+	if cfg.Insecure {
+		opts = append(opts, otlploggrpc.WithInsecure())
+	}
+	return otlploggrpc.New(ctx, opts...)
+}
+
+// logsEndpoint resolves OTEL_EXPORTER_OTLP_LOGS_ENDPOINT for logs-specific
+// overrides, falling back to cfg.Endpoint (already resolved for cfg.Protocol
+// by config.Load) so the log pipeline tracks the same config as metrics.
+func logsEndpoint(cfg *config.Config) string {
+	if endpoint := os.Getenv("OTEL_EXPORTER_OTLP_LOGS_ENDPOINT"); endpoint != "" {
+		return endpoint
+	}
+	return cfg.Endpoint
+}
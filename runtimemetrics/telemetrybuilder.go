@@ -0,0 +1,195 @@
+// Package runtimemetrics provides a small, mdatagen-style telemetry builder
+// for the process/runtime observables that otel-sla reports about itself:
+// CPU time, RSS, and Go runtime internals (heap, goroutines, GC).
+package runtimemetrics
+
+import (
+	"context"
+	"fmt"
+	"runtime"
+	"syscall"
+	"time"
+
+	"go.opentelemetry.io/otel/metric"
+)
+
+// ProcessCPUSecondsFunc returns cumulative user+system CPU time in seconds.
+type ProcessCPUSecondsFunc func() (float64, error)
+
+// ProcessMemoryRSSFunc returns resident set size in bytes.
+type ProcessMemoryRSSFunc func() (int64, error)
+
+// TelemetryBuilder registers the standard set of process/runtime
+// observables against a metric.Meter. Construct one with
+// NewTelemetryBuilder and call Register once the meter is ready.
+type TelemetryBuilder struct {
+	meter metric.Meter
+	start time.Time
+
+	processCPUSeconds ProcessCPUSecondsFunc
+	processMemoryRSS  ProcessMemoryRSSFunc
+}
+
+// Option configures a TelemetryBuilder.
+type Option func(*TelemetryBuilder)
+
+// WithProcessCPUSecondsCallback overrides how process.cpu.seconds is
+// sampled, primarily so tests can inject a fake instead of syscall.Getrusage.
+func WithProcessCPUSecondsCallback(fn ProcessCPUSecondsFunc) Option {
+	return func(tb *TelemetryBuilder) {
+		tb.processCPUSeconds = fn
+	}
+}
+
+// WithProcessMemoryRSSCallback overrides how process.memory.rss is sampled.
+func WithProcessMemoryRSSCallback(fn ProcessMemoryRSSFunc) Option {
+	return func(tb *TelemetryBuilder) {
+		tb.processMemoryRSS = fn
+	}
+}
+
+// NewTelemetryBuilder constructs a TelemetryBuilder for meter, applying the
+// default rusage-based CPU/RSS callbacks unless overridden by opts.
+func NewTelemetryBuilder(meter metric.Meter, opts ...Option) *TelemetryBuilder {
+	tb := &TelemetryBuilder{
+		meter:             meter,
+		start:             time.Now(),
+		processCPUSeconds: defaultProcessCPUSeconds,
+		processMemoryRSS:  defaultProcessMemoryRSS,
+	}
+	for _, opt := range opts {
+		opt(tb)
+	}
+	return tb
+}
+
+// Register creates the instruments and registers the single callback that
+// backs all of them, reading runtime.MemStats once per collection instead
+// of once per instrument.
+func (tb *TelemetryBuilder) Register() error {
+	cpuSeconds, err := tb.meter.Float64ObservableCounter(
+		"process.cpu.seconds",
+		metric.WithDescription("Total CPU seconds consumed by the process"),
+		metric.WithUnit("s"),
+	)
+	if err != nil {
+		return fmt.Errorf("could not create process.cpu.seconds: %w", err)
+	}
+
+	memoryRSS, err := tb.meter.Int64ObservableGauge(
+		"process.memory.rss",
+		metric.WithDescription("Resident set size"),
+		metric.WithUnit("By"),
+	)
+	if err != nil {
+		return fmt.Errorf("could not create process.memory.rss: %w", err)
+	}
+
+	heapAlloc, err := tb.meter.Int64ObservableGauge(
+		"process.runtime.go.heap_alloc_bytes",
+		metric.WithDescription("Bytes of allocated heap objects"),
+		metric.WithUnit("By"),
+	)
+	if err != nil {
+		return fmt.Errorf("could not create process.runtime.go.heap_alloc_bytes: %w", err)
+	}
+
+	totalAlloc, err := tb.meter.Int64ObservableCounter(
+		"process.runtime.go.total_alloc_bytes",
+		metric.WithDescription("Cumulative bytes allocated for heap objects"),
+		metric.WithUnit("By"),
+	)
+	if err != nil {
+		return fmt.Errorf("could not create process.runtime.go.total_alloc_bytes: %w", err)
+	}
+
+	sysBytes, err := tb.meter.Int64ObservableGauge(
+		"process.runtime.go.sys_bytes",
+		metric.WithDescription("Total bytes of memory obtained from the OS"),
+		metric.WithUnit("By"),
+	)
+	if err != nil {
+		return fmt.Errorf("could not create process.runtime.go.sys_bytes: %w", err)
+	}
+
+	goroutines, err := tb.meter.Int64ObservableGauge(
+		"process.runtime.go.goroutines",
+		metric.WithDescription("Number of goroutines that currently exist"),
+		metric.WithUnit("{goroutine}"),
+	)
+	if err != nil {
+		return fmt.Errorf("could not create process.runtime.go.goroutines: %w", err)
+	}
+
+	gcCount, err := tb.meter.Int64ObservableCounter(
+		"process.runtime.go.gc.count",
+		metric.WithDescription("Number of completed garbage collection cycles"),
+		metric.WithUnit("{gc}"),
+	)
+	if err != nil {
+		return fmt.Errorf("could not create process.runtime.go.gc.count: %w", err)
+	}
+
+	uptime, err := tb.meter.Float64ObservableCounter(
+		"process.uptime",
+		metric.WithDescription("Seconds since the process started"),
+		metric.WithUnit("s"),
+	)
+	if err != nil {
+		return fmt.Errorf("could not create process.uptime: %w", err)
+	}
+
+	_, err = tb.meter.RegisterCallback(func(_ context.Context, o metric.Observer) error {
+		var memStats runtime.MemStats
+		runtime.ReadMemStats(&memStats)
+
+		if cpuSecondsValue, err := tb.processCPUSeconds(); err != nil {
+			return fmt.Errorf("could not read process cpu seconds: %w", err)
+		} else {
+			o.ObserveFloat64(cpuSeconds, cpuSecondsValue)
+		}
+
+		if rss, err := tb.processMemoryRSS(); err != nil {
+			return fmt.Errorf("could not read process memory rss: %w", err)
+		} else {
+			o.ObserveInt64(memoryRSS, rss)
+		}
+
+		o.ObserveInt64(heapAlloc, int64(memStats.HeapAlloc))
+		o.ObserveInt64(totalAlloc, int64(memStats.TotalAlloc))
+		o.ObserveInt64(sysBytes, int64(memStats.Sys))
+		o.ObserveInt64(goroutines, int64(runtime.NumGoroutine()))
+		o.ObserveInt64(gcCount, int64(memStats.NumGC))
+		o.ObserveFloat64(uptime, time.Since(tb.start).Seconds())
+
+		return nil
+	}, cpuSeconds, memoryRSS, heapAlloc, totalAlloc, sysBytes, goroutines, gcCount, uptime)
+	if err != nil {
+		return fmt.Errorf("could not register runtime telemetry callback: %w", err)
+	}
+
+	return nil
+}
+
+// defaultProcessCPUSeconds sums user and system CPU time via
+// syscall.Getrusage(RUSAGE_SELF, ...).
+func defaultProcessCPUSeconds() (float64, error) {
+	var usage syscall.Rusage
+	if err := syscall.Getrusage(syscall.RUSAGE_SELF, &usage); err != nil {
+		return 0, fmt.Errorf("could not get rusage: %w", err)
+	}
+	userSeconds := float64(usage.Utime.Sec) + float64(usage.Utime.Usec)/1e6
+	sysSeconds := float64(usage.Stime.Sec) + float64(usage.Stime.Usec)/1e6
+	return userSeconds + sysSeconds, nil
+}
+
+// defaultProcessMemoryRSS reports the process's peak resident set size via
+// syscall.Getrusage (Maxrss, in kilobytes on Linux); it is the high-water
+// mark since start, not the current RSS.
+func defaultProcessMemoryRSS() (int64, error) {
+	var usage syscall.Rusage
+	if err := syscall.Getrusage(syscall.RUSAGE_SELF, &usage); err != nil {
+		return 0, fmt.Errorf("could not get rusage: %w", err)
+	}
+	return usage.Maxrss * 1024, nil
+}
@@ -0,0 +1,104 @@
+package runtimemetrics
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	metricsdk "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+)
+
+// collect registers tb against a manual reader and returns the collected
+// metrics for a single round.
+func collect(t *testing.T, tb *TelemetryBuilder) metricdata.ResourceMetrics {
+	t.Helper()
+
+	reader := metricsdk.NewManualReader()
+	provider := metricsdk.NewMeterProvider(metricsdk.WithReader(reader))
+	t.Cleanup(func() { _ = provider.Shutdown(context.Background()) })
+
+	tb.meter = provider.Meter("runtimemetrics-test")
+	if err := tb.Register(); err != nil {
+		t.Fatalf("Register() error = %v", err)
+	}
+
+	var rm metricdata.ResourceMetrics
+	if err := reader.Collect(context.Background(), &rm); err != nil {
+		t.Fatalf("Collect() error = %v", err)
+	}
+	return rm
+}
+
+func float64Value(t *testing.T, rm metricdata.ResourceMetrics, name string) float64 {
+	t.Helper()
+	for _, sm := range rm.ScopeMetrics {
+		for _, m := range sm.Metrics {
+			if m.Name != name {
+				continue
+			}
+			switch data := m.Data.(type) {
+			case metricdata.Sum[float64]:
+				return data.DataPoints[0].Value
+			}
+		}
+	}
+	t.Fatalf("metric %q not found", name)
+	return 0
+}
+
+func int64Value(t *testing.T, rm metricdata.ResourceMetrics, name string) int64 {
+	t.Helper()
+	for _, sm := range rm.ScopeMetrics {
+		for _, m := range sm.Metrics {
+			if m.Name != name {
+				continue
+			}
+			switch data := m.Data.(type) {
+			case metricdata.Gauge[int64]:
+				return data.DataPoints[0].Value
+			case metricdata.Sum[int64]:
+				return data.DataPoints[0].Value
+			}
+		}
+	}
+	t.Fatalf("metric %q not found", name)
+	return 0
+}
+
+func TestTelemetryBuilderUsesInjectedCallbacks(t *testing.T) {
+	tb := NewTelemetryBuilder(nil,
+		WithProcessCPUSecondsCallback(func() (float64, error) { return 42.5, nil }),
+		WithProcessMemoryRSSCallback(func() (int64, error) { return 123456, nil }),
+	)
+
+	rm := collect(t, tb)
+
+	if got := float64Value(t, rm, "process.cpu.seconds"); got != 42.5 {
+		t.Errorf("process.cpu.seconds = %v, want 42.5", got)
+	}
+	if got := int64Value(t, rm, "process.memory.rss"); got != 123456 {
+		t.Errorf("process.memory.rss = %v, want 123456", got)
+	}
+}
+
+func TestTelemetryBuilderPropagatesCallbackErrors(t *testing.T) {
+	wantErr := errors.New("rusage unavailable")
+	tb := NewTelemetryBuilder(nil,
+		WithProcessCPUSecondsCallback(func() (float64, error) { return 0, wantErr }),
+	)
+
+	reader := metricsdk.NewManualReader()
+	provider := metricsdk.NewMeterProvider(metricsdk.WithReader(reader))
+	t.Cleanup(func() { _ = provider.Shutdown(context.Background()) })
+
+	tb.meter = provider.Meter("runtimemetrics-test")
+	if err := tb.Register(); err != nil {
+		t.Fatalf("Register() error = %v", err)
+	}
+
+	var rm metricdata.ResourceMetrics
+	if err := reader.Collect(context.Background(), &rm); !errors.Is(err, wantErr) {
+		t.Errorf("Collect() error = %v, want it to wrap %v", err, wantErr)
+	}
+}